@@ -0,0 +1,103 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// getDownstreamVolumeSnapshotChain returns every volume-snapshot Backup that
+// starts after backup and shares its source volume set, ordered oldest
+// first. Deleting a mid-chain snapshot can shift referenced bytes across
+// several successors (the immediate successor absorbs blocks the deleted
+// snapshot shared with it, and further successors may in turn share with
+// that successor), so callers should recompute size for the whole chain
+// rather than only the first entry.
+//
+// The walk stops early once bm.ChainRecomputeLimit entries have been
+// collected (0 means unlimited), since walking an unbounded chain on every
+// clean run can get expensive.
+func (bm *Manager) getDownstreamVolumeSnapshotChain(ctx context.Context, backup *v1alpha1.Backup) []*v1alpha1.Backup {
+	bks, err := bm.backupLister.Backups(backup.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	// sort the backup list by TimeStarted, since volume snapshot is point-in-time (start time) backup
+	sort.Slice(bks, func(i, j int) bool {
+		return bks[i].Status.TimeStarted.Before(&bks[j].Status.TimeStarted)
+	})
+
+	idx := -1
+	for i, bk := range bks {
+		if backup.Name == bk.Name {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return nil
+	}
+
+	var chain []*v1alpha1.Backup
+	for _, bk := range bks[idx+1:] {
+		if bk.Spec.Mode != v1alpha1.BackupModeVolumeSnapshot {
+			continue
+		}
+		if !sameVolumeSnapshotSource(backup, bk) {
+			continue
+		}
+		chain = append(chain, bk)
+		if bm.ChainRecomputeLimit > 0 && len(chain) >= bm.ChainRecomputeLimit {
+			break
+		}
+	}
+	return chain
+}
+
+// sameVolumeSnapshotSource reports whether two volume-snapshot backups were
+// taken of the same source cluster, and so can share incremental blocks.
+func sameVolumeSnapshotSource(a, b *v1alpha1.Backup) bool {
+	return a.Spec.BR != nil && b.Spec.BR != nil &&
+		a.Spec.BR.ClusterNamespace == b.Spec.BR.ClusterNamespace &&
+		a.Spec.BR.Cluster == b.Spec.BR.Cluster
+}
+
+// calcVolSnapBackupSizeCached wraps util.CalcVolSnapBackupSize with a cache
+// keyed by snapshot ID (the backup's own name, which maps 1:1 onto its
+// volume-snapshot set), since a single clean run may ask for the same
+// shared-block enumeration more than once while walking a chain.
+func (bm *Manager) calcVolSnapBackupSizeCached(ctx context.Context, backup *v1alpha1.Backup) (int64, error) {
+	bm.snapshotSizeCacheMu.Lock()
+	if size, ok := bm.snapshotSizeCache[backup.Name]; ok {
+		bm.snapshotSizeCacheMu.Unlock()
+		return size, nil
+	}
+	bm.snapshotSizeCacheMu.Unlock()
+
+	size, err := util.CalcVolSnapBackupSize(ctx, backup.Spec.StorageProvider)
+	if err != nil {
+		return size, err
+	}
+
+	bm.snapshotSizeCacheMu.Lock()
+	bm.snapshotSizeCache[backup.Name] = size
+	bm.snapshotSizeCacheMu.Unlock()
+	return size, nil
+}
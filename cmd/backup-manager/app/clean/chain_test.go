@@ -0,0 +1,112 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// fakeBackupLister is a minimal listers.BackupLister backed by a plain
+// slice, enough to exercise code that only ever calls List/Get against a
+// single namespace.
+type fakeBackupLister struct {
+	namespace string
+	backups   []*v1alpha1.Backup
+}
+
+func (f *fakeBackupLister) List(selector labels.Selector) ([]*v1alpha1.Backup, error) {
+	return f.backups, nil
+}
+
+func (f *fakeBackupLister) Backups(namespace string) listers.BackupNamespaceLister {
+	return fakeBackupNamespaceLister{f}
+}
+
+type fakeBackupNamespaceLister struct {
+	*fakeBackupLister
+}
+
+func (f fakeBackupNamespaceLister) List(selector labels.Selector) ([]*v1alpha1.Backup, error) {
+	return f.backups, nil
+}
+
+func (f fakeBackupNamespaceLister) Get(name string) (*v1alpha1.Backup, error) {
+	for _, bk := range f.backups {
+		if bk.Name == name {
+			return bk, nil
+		}
+	}
+	return nil, errors.NewNotFound(v1alpha1.SchemeGroupVersion.WithResource("backups").GroupResource(), name)
+}
+
+func volSnapBackupAt(name string, t time.Time, ns, cluster string) *v1alpha1.Backup {
+	return &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+		Spec: v1alpha1.BackupSpec{
+			Mode: v1alpha1.BackupModeVolumeSnapshot,
+			BR:   &v1alpha1.BRConfig{ClusterNamespace: ns, Cluster: cluster},
+		},
+		Status: v1alpha1.BackupStatus{TimeStarted: metav1.NewTime(t)},
+	}
+}
+
+func TestGetDownstreamVolumeSnapshotChain(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b0 := volSnapBackupAt("b0", base, "ns", "cluster")
+	b1 := volSnapBackupAt("b1", base.AddDate(0, 0, 1), "ns", "cluster")
+	b2 := volSnapBackupAt("b2", base.AddDate(0, 0, 2), "ns", "cluster")
+	other := volSnapBackupAt("other-cluster", base.AddDate(0, 0, 1), "ns", "another-cluster")
+
+	bm := &Manager{
+		backupLister: &fakeBackupLister{backups: []*v1alpha1.Backup{b0, b1, b2, other}},
+	}
+
+	chain := bm.getDownstreamVolumeSnapshotChain(context.Background(), b0)
+	if len(chain) != 2 || chain[0].Name != "b1" || chain[1].Name != "b2" {
+		t.Fatalf("expected chain [b1 b2], got %v", names(chain))
+	}
+}
+
+func TestGetDownstreamVolumeSnapshotChainRespectsLimit(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b0 := volSnapBackupAt("b0", base, "ns", "cluster")
+	b1 := volSnapBackupAt("b1", base.AddDate(0, 0, 1), "ns", "cluster")
+	b2 := volSnapBackupAt("b2", base.AddDate(0, 0, 2), "ns", "cluster")
+
+	bm := &Manager{
+		backupLister: &fakeBackupLister{backups: []*v1alpha1.Backup{b0, b1, b2}},
+		Options:      Options{ChainRecomputeLimit: 1},
+	}
+
+	chain := bm.getDownstreamVolumeSnapshotChain(context.Background(), b0)
+	if len(chain) != 1 || chain[0].Name != "b1" {
+		t.Fatalf("expected chain capped to [b1], got %v", names(chain))
+	}
+}
+
+func TestGetDownstreamVolumeSnapshotChainUnknownBackup(t *testing.T) {
+	bm := &Manager{backupLister: &fakeBackupLister{}}
+	missing := &v1alpha1.Backup{ObjectMeta: metav1.ObjectMeta{Name: "missing"}}
+	if chain := bm.getDownstreamVolumeSnapshotChain(context.Background(), missing); chain != nil {
+		t.Fatalf("expected nil chain for unknown backup, got %v", names(chain))
+	}
+}
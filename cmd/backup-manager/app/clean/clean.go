@@ -0,0 +1,44 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCleanCommand returns the "clean" subcommand of backup-manager. mgr is
+// expected to already carry its listers and StatusUpdater (wired up by the
+// caller from the shared clientset/informer setup); this command only binds
+// its flags onto mgr's embedded Options.
+func NewCleanCommand(mgr *Manager) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Clean the remote data of a Backup",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return mgr.ProcessCleanBackup()
+		},
+	}
+
+	cmd.Flags().StringVar(&mgr.Namespace, "namespace", "", "Namespace of the Backup CRD object")
+	cmd.Flags().StringVar(&mgr.TidbClusterName, "cluster-name", "", "Name of the TiDB cluster the backup belongs to")
+	cmd.Flags().StringVar(&mgr.BackupName, "backupName", "", "Name of the Backup CRD object")
+	cmd.Flags().IntVar(&mgr.ChainRecomputeLimit, "chain-recompute-limit", 0,
+		"Cap how many downstream volume-snapshot backups have their size recomputed per clean run (0 means unlimited)")
+	cmd.Flags().DurationVar(&mgr.DefaultCleanTimeout, "clean-timeout", 0,
+		"Default per-backup timeout for a clean run, used when the Backup has no clean-timeout annotation of its own (0 means no timeout)")
+	cmd.Flags().BoolVar(&mgr.DryRun, "dry-run", false,
+		"Only report what clean would remove instead of performing any destructive call")
+
+	return cmd
+}
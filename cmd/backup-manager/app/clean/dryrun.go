@@ -0,0 +1,106 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// cleanPlanEntry describes one object a dry run would have removed.
+type cleanPlanEntry struct {
+	Backup        string `json:"backup"`
+	URI           string `json:"uri"`
+	FreedBytes    int64  `json:"freedBytes"`
+	FreedReadable string `json:"freedReadable"`
+
+	// UpperBound is true when FreedBytes is the victim's own size rather
+	// than bytes actually reclaimed. This happens for volume-snapshot
+	// backups: deleting a mid-chain snapshot mostly shifts its blocks onto
+	// a downstream successor instead of freeing them, and this package has
+	// no way to simulate that reshuffle without performing the delete, so
+	// FreedBytes overstates the real freed bytes for those entries.
+	UpperBound bool `json:"upperBound,omitempty"`
+}
+
+// planCleanVictims estimates, without performing any destructive call, what
+// cleaning each of victims would remove. It mirrors the routing
+// cleanSingleBackup uses so the plan reflects BR, volume-snapshot and
+// legacy backups the same way a real run would.
+func (bm *Manager) planCleanVictims(ctx context.Context, victims []*v1alpha1.Backup) []cleanPlanEntry {
+	plan := make([]cleanPlanEntry, 0, len(victims))
+	for _, victim := range victims {
+		plan = append(plan, bm.planSingleBackup(ctx, victim))
+	}
+	return plan
+}
+
+// planSingleBackup estimates the freed bytes and remote URI for a single
+// backup's data. Volume-snapshot backups recompute size the same way a real
+// clean would (their incremental blocks can only be known by enumerating
+// them); BR and legacy backups already carry their size on Status from the
+// backup run itself, so that's used directly instead of re-deriving it.
+//
+// For volume-snapshot backups the reported size is only an upper bound on
+// what cleaning would actually free: getDownstreamVolumeSnapshotChain's own
+// rationale is that deleting a mid-chain snapshot shifts its blocks onto a
+// downstream successor rather than freeing them outright, and estimating
+// the real delta would mean recomputing every downstream backup's size as
+// if the victim were already gone, which this package can't do without
+// performing the delete. UpperBound flags this so callers don't read
+// FreedBytes as bytes actually reclaimed.
+func (bm *Manager) planSingleBackup(ctx context.Context, backup *v1alpha1.Backup) cleanPlanEntry {
+	var size int64
+	upperBound := backup.Spec.Mode == v1alpha1.BackupModeVolumeSnapshot
+	if upperBound {
+		var err error
+		size, err = bm.calcVolSnapBackupSizeCached(ctx, backup)
+		if err != nil {
+			klog.Warningf("dry-run: failed to estimate size of cluster %s backup %s, err: %v", bm, backup.Name, err)
+		}
+	} else if backup.Status.BackupSize > 0 {
+		size = backup.Status.BackupSize
+	}
+
+	return cleanPlanEntry{
+		Backup:        backup.Name,
+		URI:           backup.Status.BackupPath,
+		FreedBytes:    size,
+		FreedReadable: humanize.Bytes(uint64(size)),
+		UpperBound:    upperBound,
+	}
+}
+
+// dryRunCondition renders a clean plan into the BackupCleanPlanned
+// condition, keeping the decision to actually delete a separate step from
+// previewing it.
+func dryRunCondition(plan []cleanPlanEntry) (*v1alpha1.BackupCondition, error) {
+	msg, err := json.Marshal(plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal clean plan: %v", err)
+	}
+
+	return &v1alpha1.BackupCondition{
+		Type:    v1alpha1.BackupCleanPlanned,
+		Status:  corev1.ConditionTrue,
+		Reason:  "CleanBackupDryRun",
+		Message: string(msg),
+	}, nil
+}
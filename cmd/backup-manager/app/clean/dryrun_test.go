@@ -0,0 +1,86 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+func newTestManager() *Manager {
+	return &Manager{snapshotSizeCache: make(map[string]int64)}
+}
+
+func TestPlanSingleBackupUsesStatusSizeForBRBackup(t *testing.T) {
+	bm := newTestManager()
+	backup := &v1alpha1.Backup{
+		Spec:   v1alpha1.BackupSpec{BR: &v1alpha1.BRConfig{Cluster: "cluster"}},
+		Status: v1alpha1.BackupStatus{BackupSize: 1024, BackupPath: "s3://bucket/br"},
+	}
+
+	entry := bm.planSingleBackup(context.Background(), backup)
+	if entry.FreedBytes != 1024 {
+		t.Fatalf("got %d, want 1024 (BR backups should use Status.BackupSize, not volume-snapshot sizing)", entry.FreedBytes)
+	}
+	if entry.URI != "s3://bucket/br" {
+		t.Fatalf("got %q, want backup's BackupPath", entry.URI)
+	}
+	if entry.UpperBound {
+		t.Fatalf("expected UpperBound to be false for a BR entry, Status.BackupSize is the real size")
+	}
+}
+
+func TestPlanSingleBackupUsesStatusSizeForLegacyBackup(t *testing.T) {
+	bm := newTestManager()
+	backup := &v1alpha1.Backup{
+		Status: v1alpha1.BackupStatus{BackupSize: 2048, BackupPath: "s3://bucket/legacy"},
+	}
+
+	entry := bm.planSingleBackup(context.Background(), backup)
+	if entry.FreedBytes != 2048 {
+		t.Fatalf("got %d, want 2048", entry.FreedBytes)
+	}
+}
+
+func TestPlanSingleBackupZeroSizeForUnknownLegacyBackup(t *testing.T) {
+	bm := newTestManager()
+	backup := &v1alpha1.Backup{Status: v1alpha1.BackupStatus{BackupPath: "s3://bucket/legacy"}}
+
+	entry := bm.planSingleBackup(context.Background(), backup)
+	if entry.FreedBytes != 0 {
+		t.Fatalf("got %d, want 0 when Status.BackupSize is unset", entry.FreedBytes)
+	}
+}
+
+func TestPlanSingleBackupUsesVolSnapSizingForVolumeSnapshotBackup(t *testing.T) {
+	bm := newTestManager()
+	backup := &v1alpha1.Backup{
+		Spec:   v1alpha1.BackupSpec{Mode: v1alpha1.BackupModeVolumeSnapshot},
+		Status: v1alpha1.BackupStatus{BackupSize: 999, BackupPath: "s3://bucket/snap"},
+	}
+	// Pre-seed the cache so calcVolSnapBackupSizeCached doesn't have to
+	// reach out through util.CalcVolSnapBackupSize, which isn't available
+	// in this test binary.
+	bm.snapshotSizeCache[""] = 4096
+
+	entry := bm.planSingleBackup(context.Background(), backup)
+	if entry.FreedBytes != 4096 {
+		t.Fatalf("got %d, want 4096 from volume-snapshot sizing, not Status.BackupSize %d", entry.FreedBytes, backup.Status.BackupSize)
+	}
+	if !entry.UpperBound {
+		t.Fatalf("expected UpperBound to be true for a volume-snapshot entry")
+	}
+}
@@ -16,7 +16,7 @@ package clean
 import (
 	"context"
 	"fmt"
-	"sort"
+	"sync"
 
 	"github.com/dustin/go-humanize"
 	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
@@ -24,31 +24,40 @@ import (
 	listers "github.com/pingcap/tidb-operator/pkg/client/listers/pingcap/v1alpha1"
 	"github.com/pingcap/tidb-operator/pkg/controller"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	errorutils "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 )
 
 // Manager mainly used to manage backup related work
 type Manager struct {
-	backupLister  listers.BackupLister
-	StatusUpdater controller.BackupConditionUpdaterInterface
+	backupLister       listers.BackupLister
+	backupTargetLister listers.BackupTargetLister
+	StatusUpdater      controller.BackupConditionUpdaterInterface
 	Options
+
+	snapshotSizeCache   map[string]int64
+	snapshotSizeCacheMu sync.Mutex
 }
 
 // NewManager return a Manager
 func NewManager(
 	backupLister listers.BackupLister,
+	backupTargetLister listers.BackupTargetLister,
 	statusUpdater controller.BackupConditionUpdaterInterface,
 	backupOpts Options) *Manager {
 	return &Manager{
-		backupLister,
-		statusUpdater,
-		backupOpts,
+		backupLister:       backupLister,
+		backupTargetLister: backupTargetLister,
+		StatusUpdater:      statusUpdater,
+		Options:            backupOpts,
+		snapshotSizeCache:  make(map[string]int64),
 	}
 }
 
-// ProcessCleanBackup used to clean the specific backup
+// ProcessCleanBackup used to clean the specific backup. If the backup
+// declares a retention policy, every sibling backup is considered for
+// cleanup instead of only bm.BackupName. A policy set on the owning
+// BackupSchedule is not read (see retentionPolicyFor).
 func (bm *Manager) ProcessCleanBackup() error {
 	ctx, cancel := util.GetContextForTerminationSignals(fmt.Sprintf("clean %s", bm.BackupName))
 	defer cancel()
@@ -57,8 +66,65 @@ func (bm *Manager) ProcessCleanBackup() error {
 	if err != nil {
 		return fmt.Errorf("can't find cluster %s backup %s CRD object, err: %v", bm, bm.BackupName, err)
 	}
+	backup = backup.DeepCopy()
+
+	// cleanTimeoutFor is a per-Backup timeout, so it's applied once per
+	// backup actually cleaned (performCleanBackup for the single-backup
+	// path, cleanVictims per victim for the retention path) rather than
+	// once here, which would otherwise turn it into a deadline for the
+	// whole retention batch instead of each backup within it.
+	if policy := retentionPolicyFor(backup); policy != nil {
+		return bm.performRetentionCleanup(ctx, backup, policy)
+	}
+
+	return bm.performCleanBackup(ctx, backup)
+}
+
+// performRetentionCleanup lists every sibling backup in the namespace,
+// computes which ones fall outside policy and cleans them through the
+// existing per-backup paths, reporting the aggregate outcome on the
+// triggering backup.
+func (bm *Manager) performRetentionCleanup(ctx context.Context, backup *v1alpha1.Backup, policy *v1alpha1.RetentionPolicy) error {
+	siblings, err := bm.listSiblingBackups(backup.Namespace)
+	if err != nil {
+		return fmt.Errorf("can't list sibling backups of cluster %s, err: %v", bm, err)
+	}
+
+	_, victims := computeRetentionVictims(policy, siblings)
+	if len(victims) == 0 {
+		klog.Infof("retention policy for cluster %s keeps all %d backups, nothing to clean", bm, len(siblings))
+		return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
+			Type:   v1alpha1.BackupClean,
+			Status: corev1.ConditionTrue,
+		}, nil)
+	}
+
+	klog.Infof("retention policy for cluster %s selected %d of %d backups for cleanup", bm, len(victims), len(siblings))
+
+	if bm.DryRun {
+		plan := bm.planCleanVictims(ctx, victims)
+		cond, err := dryRunCondition(plan)
+		if err != nil {
+			return err
+		}
+		klog.Infof("dry-run: retention policy for cluster %s would clean %d of %d backups", bm, len(victims), len(siblings))
+		return bm.StatusUpdater.Update(backup, cond, nil)
+	}
+
+	if err := bm.cleanVictims(ctx, victims); err != nil {
+		klog.Errorf("retention cleanup for cluster %s failed, err: %s", bm, err)
+		return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
+			Type:    v1alpha1.BackupFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  cleanFailureReason(ctx, err),
+			Message: err.Error(),
+		}, nil)
+	}
 
-	return bm.performCleanBackup(ctx, backup.DeepCopy())
+	return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
+		Type:   v1alpha1.BackupClean,
+		Status: corev1.ConditionTrue,
+	}, nil)
 }
 
 func (bm *Manager) performCleanBackup(ctx context.Context, backup *v1alpha1.Backup) error {
@@ -72,16 +138,65 @@ func (bm *Manager) performCleanBackup(ctx context.Context, backup *v1alpha1.Back
 		}, nil)
 	}
 
-	var errs []error
+	if bm.DryRun {
+		plan := bm.planCleanVictims(ctx, []*v1alpha1.Backup{backup})
+		cond, err := dryRunCondition(plan)
+		if err != nil {
+			return err
+		}
+		klog.Infof("dry-run: cluster %s backup %s would be cleaned", bm, backup.Status.BackupPath)
+		return bm.StatusUpdater.Update(backup, cond, nil)
+	}
+
+	if timeout := bm.cleanTimeoutFor(backup); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cond, err := bm.cleanSingleBackup(ctx, backup)
+	if err != nil {
+		klog.Errorf("clean cluster %s backup %s failed, err: %s", bm, backup.Status.BackupPath, err)
+		if cond == nil {
+			cond = &v1alpha1.BackupCondition{
+				Type:    v1alpha1.BackupFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  cleanFailureReason(ctx, err),
+				Message: err.Error(),
+			}
+		}
+		uerr := bm.StatusUpdater.Update(backup, cond, nil)
+		return errorutils.NewAggregate([]error{err, uerr})
+	}
+
+	klog.Infof("clean cluster %s backup %s success", bm, backup.Status.BackupPath)
+	if cond == nil {
+		cond = &v1alpha1.BackupCondition{
+			Type:   v1alpha1.BackupClean,
+			Status: corev1.ConditionTrue,
+		}
+	}
+	return bm.StatusUpdater.Update(backup, cond, nil)
+}
+
+// cleanSingleBackup deletes one backup's remote data through the existing
+// snapshot/BR/legacy paths without touching its status, so it can be shared
+// by the one-off clean path above and by retention-driven batch cleanup in
+// cleanVictims. A non-nil BackupCondition it returns is reported in place
+// of the caller's default Clean/Failed condition; for a multi-target backup
+// this is BackupClean when every target succeeded and CleanBackupPartial
+// when some targets failed and some didn't.
+func (bm *Manager) cleanSingleBackup(ctx context.Context, backup *v1alpha1.Backup) (*v1alpha1.BackupCondition, error) {
+	if backup.Status.BackupPath == "" {
+		return nil, fmt.Errorf("cluster %s backup %s path is empty", bm, backup.Name)
+	}
+
 	var err error
 	// volume-snapshot backup requires to delete the snapshot firstly, then delete the backup meta file
 	// volume-snapshot is incremental snapshot per volume. Any backup deletion will take effects on next volume-snapshot backup
 	// we need update backup size of the impacted the volume-snapshot backup.
 	if backup.Spec.Mode == v1alpha1.BackupModeVolumeSnapshot {
-		nextNackup := bm.getNextBackup(ctx, backup)
-		if nextNackup == nil {
-			klog.Errorf("get next backup for cluster %s backup is nil", bm)
-		}
+		chain := bm.getDownstreamVolumeSnapshotChain(ctx, backup)
 
 		// clean backup will delete all vol snapshots
 		err = bm.cleanBackupMetaWithVolSnapshots(ctx, backup)
@@ -89,79 +204,43 @@ func (bm *Manager) performCleanBackup(ctx context.Context, backup *v1alpha1.Back
 			klog.Errorf("delete backup %s for cluster %s backup failure", backup.Name, bm)
 		}
 
-		// update the next backup size
-		if nextNackup != nil {
-			bm.updateVolumeSnapshotBackupSize(ctx, nextNackup)
+		// deleting a mid-chain snapshot can shift referenced bytes across every
+		// downstream successor, not just the immediate next one, so recompute
+		// size for the whole remaining chain; sizes cached earlier in this run
+		// predate the delete, so they are dropped first
+		bm.snapshotSizeCacheMu.Lock()
+		for _, downstream := range chain {
+			delete(bm.snapshotSizeCache, downstream.Name)
 		}
-
-	} else {
-		if backup.Spec.BR != nil {
-			err = bm.CleanBRRemoteBackupData(ctx, backup)
-		} else {
-			opts := util.GetOptions(backup.Spec.StorageProvider)
-			err = bm.cleanRemoteBackupData(ctx, backup.Status.BackupPath, opts)
+		bm.snapshotSizeCacheMu.Unlock()
+		for _, downstream := range chain {
+			if uerr := bm.updateVolumeSnapshotBackupSize(ctx, downstream); uerr != nil {
+				klog.Errorf("update size of downstream backup %s for cluster %s failed: %v", downstream.Name, bm, uerr)
+			}
 		}
-	}
 
-	if err != nil {
-		errs = append(errs, err)
-		klog.Errorf("clean cluster %s backup %s failed, err: %s", bm, backup.Status.BackupPath, err)
-		uerr := bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
-			Type:    v1alpha1.BackupFailed,
-			Status:  corev1.ConditionTrue,
-			Reason:  "CleanBackupDataFailed",
-			Message: err.Error(),
-		}, nil)
-		errs = append(errs, uerr)
-		return errorutils.NewAggregate(errs)
+		return nil, err
 	}
 
-	klog.Infof("clean cluster %s backup %s success", bm, backup.Status.BackupPath)
-	return bm.StatusUpdater.Update(backup, &v1alpha1.BackupCondition{
-		Type:   v1alpha1.BackupClean,
-		Status: corev1.ConditionTrue,
-	}, nil)
-}
-
-// getNextBackup to get next backup sorted by start time
-func (bm *Manager) getNextBackup(ctx context.Context, backup *v1alpha1.Backup) *v1alpha1.Backup {
-	var err error
-	bks, err := bm.backupLister.Backups(backup.Namespace).List(labels.Everything())
+	targets, err := bm.resolveBackupTargets(backup)
 	if err != nil {
-		return nil
-	}
-
-	// sort the backup list by TimeStarted, since volume snapshot is point-in-time (start time) backup
-	sort.Slice(bks, func(i, j int) bool {
-		return bks[i].Status.TimeStarted.Before(&bks[j].Status.TimeStarted)
-	})
-
-	for i, bk := range bks {
-		if backup.Name == bk.Name {
-			return bm.getVolumeSnapshotBackup(bks[i+1:])
-		}
+		return nil, err
 	}
 
-	return nil
-}
-
-// getVolumeSnapshotBackup get the first volume-snapshot backup from backup list, which may contain non-volume snapshot
-func (bm *Manager) getVolumeSnapshotBackup(backups []*v1alpha1.Backup) *v1alpha1.Backup {
-	for _, bk := range backups {
-		if bk.Spec.Mode == v1alpha1.BackupModeVolumeSnapshot {
-			return bk
-		}
+	if backup.Spec.BR != nil {
+		results := bm.cleanBRBackupTargets(ctx, backup, targets)
+		return partialCleanCondition(results)
 	}
 
-	// reach end of backup list, there is no volume snapshot backups
-	return nil
+	results := bm.cleanBackupTargets(ctx, backup, targets)
+	return partialCleanCondition(results)
 }
 
 // updateVolumeSnapshotBackupSize update a volume-snapshot backup size
 func (bm *Manager) updateVolumeSnapshotBackupSize(ctx context.Context, backup *v1alpha1.Backup) error {
 	var updateStatus *controller.BackupUpdateStatus
 
-	backupSize, err := util.CalcVolSnapBackupSize(ctx, backup.Spec.StorageProvider)
+	backupSize, err := bm.calcVolSnapBackupSizeCached(ctx, backup)
 
 	if err != nil {
 		klog.Warningf("Failed to parse BackupSize %d KB, %v", backupSize, err)
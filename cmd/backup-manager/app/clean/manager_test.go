@@ -0,0 +1,113 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	"github.com/pingcap/tidb-operator/pkg/controller"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeStatusUpdater records the last condition Update set per backup name,
+// so tests can assert on a victim's own status instead of only the
+// triggering backup's.
+type fakeStatusUpdater struct {
+	updates map[string]*v1alpha1.BackupCondition
+}
+
+func newFakeStatusUpdater() *fakeStatusUpdater {
+	return &fakeStatusUpdater{updates: make(map[string]*v1alpha1.BackupCondition)}
+}
+
+func (f *fakeStatusUpdater) Update(backup *v1alpha1.Backup, condition *v1alpha1.BackupCondition, _ *controller.BackupUpdateStatus) error {
+	f.updates[backup.Name] = condition
+	return nil
+}
+
+func TestCleanVictimsRecordsPerVictimStatus(t *testing.T) {
+	updater := newFakeStatusUpdater()
+	bm := &Manager{StatusUpdater: updater}
+
+	// An empty BackupPath makes cleanSingleBackup fail deterministically
+	// without reaching any of the real delete paths, which live outside
+	// this trimmed tree and can't be exercised from a unit test.
+	victims := []*v1alpha1.Backup{
+		{ObjectMeta: metav1.ObjectMeta{Name: "v0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "v1"}},
+	}
+
+	if err := bm.cleanVictims(context.Background(), victims); err == nil {
+		t.Fatal("expected an aggregate error, both victims have an empty BackupPath")
+	}
+
+	for _, name := range []string{"v0", "v1"} {
+		cond, ok := updater.updates[name]
+		if !ok {
+			t.Fatalf("expected a status update recorded for victim %s, found none", name)
+		}
+		if cond.Type != v1alpha1.BackupFailed {
+			t.Fatalf("victim %s: got condition type %s, want Failed", name, cond.Type)
+		}
+	}
+}
+
+func TestVictimCleanOutcomeTagsOwnContextTimeout(t *testing.T) {
+	victimCtx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-victimCtx.Done()
+
+	cond, err := victimCleanOutcome(victimCtx, nil, errors.New("backend unavailable"))
+	if err == nil {
+		t.Fatal("expected a non-nil error")
+	}
+	if cond.Reason != "CleanBackupTimeout" {
+		t.Fatalf("got reason %q, want CleanBackupTimeout", cond.Reason)
+	}
+}
+
+func TestVictimCleanOutcomeNonTimeoutFailure(t *testing.T) {
+	cond, err := victimCleanOutcome(context.Background(), nil, errors.New("backend unavailable"))
+	if err == nil {
+		t.Fatal("expected a non-nil error")
+	}
+	if cond.Reason != "CleanBackupDataFailed" {
+		t.Fatalf("got reason %q, want CleanBackupDataFailed", cond.Reason)
+	}
+}
+
+func TestVictimCleanOutcomeSuccessDefaultsToClean(t *testing.T) {
+	cond, err := victimCleanOutcome(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cond.Type != v1alpha1.BackupClean || cond.Status != corev1.ConditionTrue {
+		t.Fatalf("got %+v, want a true BackupClean condition", cond)
+	}
+}
+
+func TestVictimCleanOutcomePreservesCallerCondition(t *testing.T) {
+	partial := &v1alpha1.BackupCondition{Type: v1alpha1.CleanBackupPartial, Status: corev1.ConditionTrue}
+	cond, err := victimCleanOutcome(context.Background(), partial, errors.New("one target failed"))
+	if err == nil {
+		t.Fatal("expected a non-nil error")
+	}
+	if cond != partial {
+		t.Fatalf("expected the caller-supplied partial condition to be kept as-is, got %+v", cond)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"fmt"
+	"time"
+)
+
+// Options holds the flag-derived configuration of a clean run.
+type Options struct {
+	Namespace       string
+	TidbClusterName string
+	BackupName      string
+
+	// ChainRecomputeLimit caps how many downstream volume-snapshot backups
+	// have their size recomputed per clean run. Zero means unlimited.
+	ChainRecomputeLimit int
+
+	// DefaultCleanTimeout bounds how long cleaning a single backup is
+	// allowed to take when that Backup doesn't set its own clean-timeout
+	// annotation; a retention run applies it to each victim separately.
+	// Zero means no timeout.
+	DefaultCleanTimeout time.Duration
+
+	// DryRun makes ProcessCleanBackup only report the objects it would have
+	// removed instead of performing any destructive call. Set by the
+	// clean subcommand's --dry-run flag.
+	DryRun bool
+}
+
+// String lets Options be used directly as the "%s" cluster identifier in
+// log messages and errors throughout the clean package.
+func (o Options) String() string {
+	return fmt.Sprintf("%s/%s", o.Namespace, o.TidbClusterName)
+}
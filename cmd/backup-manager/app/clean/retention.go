@@ -0,0 +1,285 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+// maxRetentionCleanWorkers bounds how many backups a retention policy cleans
+// concurrently, so a policy that selects a large batch doesn't overwhelm the
+// object store or the backup-manager pod.
+const maxRetentionCleanWorkers = 4
+
+// retentionPolicyFor returns the retention policy governing backup, if any.
+// Today this only reads the policy declared directly on the Backup CR;
+// a backup created by a BackupSchedule does not yet inherit the owning
+// schedule's policy (that needs a BackupSchedule lister and an owner-
+// reference walk, neither of which exist here yet), so BackupSchedule-level
+// policies currently have no effect on cleanup.
+func retentionPolicyFor(backup *v1alpha1.Backup) *v1alpha1.RetentionPolicy {
+	return backup.Spec.RetentionPolicy
+}
+
+// listSiblingBackups returns every not-yet-cleaned Backup in ns, sorted
+// oldest first by CommitTs, falling back to TimeStarted for backups without
+// a commit timestamp (e.g. volume-snapshot backups). Backups that already
+// carry a true BackupClean condition are excluded: their remote data is
+// already gone, so letting them count toward a keep-last-N/max-age decision
+// (or get re-selected as victims) would either skew retention math or retry
+// deleting objects that no longer exist.
+func (bm *Manager) listSiblingBackups(ns string) ([]*v1alpha1.Backup, error) {
+	bks, err := bm.backupLister.Backups(ns).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	live := bks[:0:0]
+	for _, bk := range bks {
+		if !isBackupClean(bk) {
+			live = append(live, bk)
+		}
+	}
+
+	sort.Slice(live, func(i, j int) bool {
+		if live[i].Status.CommitTs != live[j].Status.CommitTs {
+			return live[i].Status.CommitTs < live[j].Status.CommitTs
+		}
+		return live[i].Status.TimeStarted.Before(&live[j].Status.TimeStarted)
+	})
+	return live, nil
+}
+
+// isBackupClean reports whether backup already carries a true BackupClean
+// condition.
+func isBackupClean(backup *v1alpha1.Backup) bool {
+	for _, c := range backup.Status.Conditions {
+		if c.Type == v1alpha1.BackupClean && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// computeRetentionVictims splits backups into the set to keep and the set to
+// delete under policy. Snapshot-mode and BR-mode backups are evaluated
+// independently so a count/age limit on one mode can't starve the other.
+func computeRetentionVictims(policy *v1alpha1.RetentionPolicy, backups []*v1alpha1.Backup) (keep, victims []*v1alpha1.Backup) {
+	var snapshot, br []*v1alpha1.Backup
+	for _, bk := range backups {
+		if bk.Spec.Mode == v1alpha1.BackupModeVolumeSnapshot {
+			snapshot = append(snapshot, bk)
+		} else {
+			br = append(br, bk)
+		}
+	}
+
+	keep = append(keep, applyRetention(policy, snapshot)...)
+	keep = append(keep, applyRetention(policy, br)...)
+
+	keepSet := make(map[string]bool, len(keep))
+	for _, bk := range keep {
+		keepSet[bk.Name] = true
+	}
+	for _, bk := range backups {
+		if !keepSet[bk.Name] {
+			victims = append(victims, bk)
+		}
+	}
+	return keep, victims
+}
+
+// applyRetention returns the backups to keep out of a single mode's
+// chronologically-sorted list, per whichever rule policy sets: a GFS
+// matrix, a max age, or a keep-last-N count.
+func applyRetention(policy *v1alpha1.RetentionPolicy, backups []*v1alpha1.Backup) []*v1alpha1.Backup {
+	if len(backups) == 0 {
+		return nil
+	}
+
+	switch {
+	case policy.GFS != nil:
+		return applyGFSRetention(policy.GFS, backups)
+	case policy.MaxAge != nil:
+		cutoff := metav1.Now().Add(-policy.MaxAge.Duration)
+		var keep []*v1alpha1.Backup
+		for _, bk := range backups {
+			if bk.Status.TimeStarted.Time.After(cutoff) {
+				keep = append(keep, bk)
+			}
+		}
+		return keep
+	case policy.Count != nil:
+		n := int(*policy.Count)
+		if n >= len(backups) {
+			return backups
+		}
+		return backups[len(backups)-n:]
+	default:
+		return backups
+	}
+}
+
+// applyGFSRetention implements a grandfather-father-son matrix: the newest
+// backup in each day/week/month bucket is kept, up to the configured number
+// of buckets per granularity.
+func applyGFSRetention(gfs *v1alpha1.GFSRetention, backups []*v1alpha1.Backup) []*v1alpha1.Backup {
+	keepSet := make(map[string]*v1alpha1.Backup)
+	for _, bk := range gfsBucketKeep(backups, gfs.Daily, func(t time.Time) string { return t.Format("2006-01-02") }) {
+		keepSet[bk.Name] = bk
+	}
+	for _, bk := range gfsBucketKeep(backups, gfs.Weekly, func(t time.Time) string {
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", y, w)
+	}) {
+		keepSet[bk.Name] = bk
+	}
+	for _, bk := range gfsBucketKeep(backups, gfs.Monthly, func(t time.Time) string { return t.Format("2006-01") }) {
+		keepSet[bk.Name] = bk
+	}
+
+	keep := make([]*v1alpha1.Backup, 0, len(keepSet))
+	for _, bk := range backups {
+		if _, ok := keepSet[bk.Name]; ok {
+			keep = append(keep, bk)
+		}
+	}
+	return keep
+}
+
+// gfsBucketKeep keeps the newest backup per bucket (as labeled by bucketOf),
+// capped to the most recent `buckets` distinct buckets.
+func gfsBucketKeep(backups []*v1alpha1.Backup, buckets int32, bucketOf func(time.Time) string) []*v1alpha1.Backup {
+	if buckets <= 0 {
+		return nil
+	}
+
+	newestInBucket := make(map[string]*v1alpha1.Backup)
+	var order []string
+	for _, bk := range backups {
+		key := bucketOf(bk.Status.TimeStarted.Time)
+		if _, ok := newestInBucket[key]; !ok {
+			order = append(order, key)
+		}
+		newestInBucket[key] = bk // backups is sorted oldest-first, so the last write per bucket wins
+	}
+
+	if len(order) > int(buckets) {
+		order = order[len(order)-int(buckets):]
+	}
+
+	keep := make([]*v1alpha1.Backup, 0, len(order))
+	for _, key := range order {
+		keep = append(keep, newestInBucket[key])
+	}
+	return keep
+}
+
+// cleanVictims runs the existing per-backup clean path over victims in a
+// bounded worker pool, aggregating every failure instead of stopping at the
+// first one. Each victim's own BackupClean/BackupFailed condition is
+// recorded on the victim itself (not just the aggregate result reported on
+// the triggering backup), so the next retention run's listSiblingBackups
+// pass can see it was already cleaned instead of selecting it as a victim
+// again forever.
+func (bm *Manager) cleanVictims(ctx context.Context, victims []*v1alpha1.Backup) error {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		errs  []error
+		slots = make(chan struct{}, maxRetentionCleanWorkers)
+	)
+
+	for _, victim := range victims {
+		victim := victim
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			// cleanTimeoutFor is per-Backup, so each victim gets its own
+			// deadline off the shared parent ctx instead of the whole batch
+			// racing a single deadline sized for one backup.
+			victimCtx := ctx
+			if timeout := bm.cleanTimeoutFor(victim); timeout > 0 {
+				var cancel context.CancelFunc
+				victimCtx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			cond, err := bm.cleanSingleBackup(victimCtx, victim)
+			if err != nil {
+				klog.Errorf("retention clean of backup %s/%s failed: %v", victim.Namespace, victim.Name, err)
+			}
+			cond, err = victimCleanOutcome(victimCtx, cond, err)
+
+			if uerr := bm.StatusUpdater.Update(victim, cond, nil); uerr != nil {
+				klog.Errorf("failed to update status of cleaned backup %s/%s: %v", victim.Namespace, victim.Name, uerr)
+				mu.Lock()
+				errs = append(errs, uerr)
+				mu.Unlock()
+			}
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errorutils.NewAggregate(errs)
+}
+
+// victimCleanOutcome turns cleanSingleBackup's result for one victim into
+// the BackupCondition to record on it and the error to aggregate. It tags a
+// cleanTimeoutError when victimCtx (not the outer batch context, which
+// never carries a deadline of its own) is what actually expired, so
+// cleanFailureReason can classify the failure correctly.
+func victimCleanOutcome(victimCtx context.Context, cond *v1alpha1.BackupCondition, err error) (*v1alpha1.BackupCondition, error) {
+	if err != nil {
+		if victimCtx.Err() == context.DeadlineExceeded {
+			err = &cleanTimeoutError{err: err}
+		}
+		if cond == nil {
+			cond = &v1alpha1.BackupCondition{
+				Type:    v1alpha1.BackupFailed,
+				Status:  corev1.ConditionTrue,
+				Reason:  cleanFailureReason(victimCtx, err),
+				Message: err.Error(),
+			}
+		}
+		return cond, err
+	}
+
+	if cond == nil {
+		cond = &v1alpha1.BackupCondition{
+			Type:   v1alpha1.BackupClean,
+			Status: corev1.ConditionTrue,
+		}
+	}
+	return cond, nil
+}
@@ -0,0 +1,157 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// backupAt returns a minimal, chronologically-sortable Backup for use in
+// retention tests. Backups built this way are already in the oldest-first
+// order applyRetention and applyGFSRetention expect.
+func backupAt(name string, t time.Time) *v1alpha1.Backup {
+	return &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status:     v1alpha1.BackupStatus{TimeStarted: metav1.NewTime(t)},
+	}
+}
+
+func names(bks []*v1alpha1.Backup) []string {
+	out := make([]string, 0, len(bks))
+	for _, bk := range bks {
+		out = append(out, bk.Name)
+	}
+	return out
+}
+
+func sameNames(t *testing.T, got []*v1alpha1.Backup, want ...string) {
+	t.Helper()
+	gotNames := names(got)
+	if len(gotNames) != len(want) {
+		t.Fatalf("got %v, want %v", gotNames, want)
+	}
+	seen := make(map[string]bool, len(want))
+	for _, n := range want {
+		seen[n] = true
+	}
+	for _, n := range gotNames {
+		if !seen[n] {
+			t.Fatalf("got %v, want %v", gotNames, want)
+		}
+	}
+}
+
+func TestApplyRetentionCount(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backups := []*v1alpha1.Backup{
+		backupAt("b0", base),
+		backupAt("b1", base.AddDate(0, 0, 1)),
+		backupAt("b2", base.AddDate(0, 0, 2)),
+	}
+
+	two := int32(2)
+	keep := applyRetention(&v1alpha1.RetentionPolicy{Count: &two}, backups)
+	sameNames(t, keep, "b1", "b2")
+
+	ten := int32(10)
+	keep = applyRetention(&v1alpha1.RetentionPolicy{Count: &ten}, backups)
+	sameNames(t, keep, "b0", "b1", "b2")
+}
+
+func TestApplyRetentionMaxAge(t *testing.T) {
+	now := time.Now()
+	backups := []*v1alpha1.Backup{
+		backupAt("old", now.Add(-48*time.Hour)),
+		backupAt("new", now.Add(-1*time.Hour)),
+	}
+
+	keep := applyRetention(&v1alpha1.RetentionPolicy{
+		MaxAge: &metav1.Duration{Duration: 24 * time.Hour},
+	}, backups)
+	sameNames(t, keep, "new")
+}
+
+func TestApplyRetentionNoPolicyKeepsAll(t *testing.T) {
+	backups := []*v1alpha1.Backup{
+		backupAt("a", time.Now()),
+		backupAt("b", time.Now()),
+	}
+	keep := applyRetention(&v1alpha1.RetentionPolicy{}, backups)
+	sameNames(t, keep, "a", "b")
+}
+
+func TestGfsBucketKeepZeroBucketsKeepsNothing(t *testing.T) {
+	backups := []*v1alpha1.Backup{backupAt("a", time.Now())}
+	if keep := gfsBucketKeep(backups, 0, func(time.Time) string { return "x" }); keep != nil {
+		t.Fatalf("expected nil keep set for 0 buckets, got %v", names(keep))
+	}
+}
+
+func TestGfsBucketKeepNewestPerBucketWins(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backups := []*v1alpha1.Backup{
+		backupAt("morning", day),
+		backupAt("evening", day.Add(12*time.Hour)),
+	}
+
+	keep := gfsBucketKeep(backups, 1, func(t time.Time) string { return t.Format("2006-01-02") })
+	sameNames(t, keep, "evening")
+}
+
+func TestGfsBucketKeepCapsToMostRecentBuckets(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var backups []*v1alpha1.Backup
+	for i := 0; i < 5; i++ {
+		backups = append(backups, backupAt(string(rune('a'+i)), day.AddDate(0, 0, i)))
+	}
+
+	keep := gfsBucketKeep(backups, 2, func(t time.Time) string { return t.Format("2006-01-02") })
+	sameNames(t, keep, "d", "e")
+}
+
+func TestApplyGFSRetentionUnionsGranularities(t *testing.T) {
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backups := []*v1alpha1.Backup{
+		backupAt("d0", day),
+		backupAt("d1", day.AddDate(0, 0, 1)),
+		backupAt("d2", day.AddDate(0, 0, 2)),
+	}
+
+	// Daily keeps only the newest; monthly also keeps the newest of the
+	// month, which here is the same backup, so the union should still be
+	// just the one newest backup.
+	keep := applyGFSRetention(&v1alpha1.GFSRetention{Daily: 1, Monthly: 1}, backups)
+	sameNames(t, keep, "d2")
+}
+
+func TestComputeRetentionVictimsSplitsByMode(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	snap0 := backupAt("snap0", base)
+	snap0.Spec.Mode = v1alpha1.BackupModeVolumeSnapshot
+	snap1 := backupAt("snap1", base.AddDate(0, 0, 1))
+	snap1.Spec.Mode = v1alpha1.BackupModeVolumeSnapshot
+	br0 := backupAt("br0", base)
+	br1 := backupAt("br1", base.AddDate(0, 0, 1))
+
+	one := int32(1)
+	_, victims := computeRetentionVictims(&v1alpha1.RetentionPolicy{Count: &one}, []*v1alpha1.Backup{snap0, snap1, br0, br1})
+
+	// Count: 1 applies independently to each mode, so exactly one victim
+	// should be selected per mode rather than one overall.
+	sameNames(t, victims, "snap0", "br0")
+}
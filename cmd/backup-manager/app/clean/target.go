@@ -0,0 +1,134 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb-operator/cmd/backup-manager/app/util"
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+// targetCleanResult captures the outcome of cleaning a backup from one
+// resolved BackupTarget.
+type targetCleanResult struct {
+	targetName string
+	err        error
+}
+
+// resolveBackupTargets returns the BackupTarget CRs a backup's data was
+// written to. A backup that still embeds its StorageProvider directly (the
+// pre-BackupTarget form) resolves to a single synthetic target, so the rest
+// of the clean path only has to reason about one code path.
+func (bm *Manager) resolveBackupTargets(backup *v1alpha1.Backup) ([]*v1alpha1.BackupTarget, error) {
+	if len(backup.Spec.BackupTargetNames) == 0 {
+		return []*v1alpha1.BackupTarget{{
+			Spec: v1alpha1.BackupTargetSpec{
+				StorageProvider: backup.Spec.StorageProvider,
+			},
+		}}, nil
+	}
+
+	targets := make([]*v1alpha1.BackupTarget, 0, len(backup.Spec.BackupTargetNames))
+	for _, name := range backup.Spec.BackupTargetNames {
+		target, err := bm.backupTargetLister.BackupTargets(backup.Namespace).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("can't find BackupTarget %s for cluster %s backup %s, err: %v", name, bm, backup.Name, err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// cleanPerTarget runs clean against every target, logging and collecting
+// one targetCleanResult per target so cleanBackupTargets and
+// cleanBRBackupTargets can share the same result-building shape.
+func (bm *Manager) cleanPerTarget(backup *v1alpha1.Backup, targets []*v1alpha1.BackupTarget, clean func(target *v1alpha1.BackupTarget) error) []targetCleanResult {
+	results := make([]targetCleanResult, 0, len(targets))
+	for _, target := range targets {
+		err := clean(target)
+		if err != nil {
+			klog.Errorf("clean cluster %s backup %s on target %s failed, err: %s", bm, backup.Status.BackupPath, target.Name, err)
+		}
+		results = append(results, targetCleanResult{targetName: target.Name, err: err})
+	}
+	return results
+}
+
+// cleanBackupTargets deletes backup's data from every resolved target and
+// returns one result per target, so the caller can build a partial-success
+// condition instead of collapsing everything into a single boolean.
+func (bm *Manager) cleanBackupTargets(ctx context.Context, backup *v1alpha1.Backup, targets []*v1alpha1.BackupTarget) []targetCleanResult {
+	return bm.cleanPerTarget(backup, targets, func(target *v1alpha1.BackupTarget) error {
+		opts := util.GetOptions(target.Spec.StorageProvider)
+		return bm.cleanRemoteBackupData(ctx, backup.Status.BackupPath, opts)
+	})
+}
+
+// cleanBRBackupTargets runs the BR clean path once per resolved target,
+// each against a deep copy of backup with that target's StorageProvider
+// swapped in, so a BR backup mirrored to more than one BackupTarget gets
+// cleaned everywhere it was written instead of only the provider embedded
+// directly on the Backup spec.
+func (bm *Manager) cleanBRBackupTargets(ctx context.Context, backup *v1alpha1.Backup, targets []*v1alpha1.BackupTarget) []targetCleanResult {
+	return bm.cleanPerTarget(backup, targets, func(target *v1alpha1.BackupTarget) error {
+		targetBackup := backup.DeepCopy()
+		targetBackup.Spec.StorageProvider = target.Spec.StorageProvider
+		return bm.CleanBRRemoteBackupData(ctx, targetBackup)
+	})
+}
+
+// partialCleanCondition builds the BackupCondition reflecting per-target
+// clean results: a plain BackupClean if more than one target was cleaned
+// and every one succeeded, a CleanBackupPartial listing which targets
+// failed if some but not all failed. A nil condition (a single target that
+// succeeded, or every target failed, the latter with the aggregate error)
+// tells the caller to fall back to its own default condition.
+func partialCleanCondition(results []targetCleanResult) (*v1alpha1.BackupCondition, error) {
+	var errs []error
+	var failed []string
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, r.err)
+			failed = append(failed, r.targetName)
+		}
+	}
+
+	if len(errs) == 0 {
+		if len(results) <= 1 {
+			return nil, nil
+		}
+		return &v1alpha1.BackupCondition{
+			Type:   v1alpha1.BackupClean,
+			Status: corev1.ConditionTrue,
+		}, nil
+	}
+
+	aggErr := errorutils.NewAggregate(errs)
+	if len(failed) == len(results) {
+		return nil, aggErr
+	}
+
+	return &v1alpha1.BackupCondition{
+		Type:    v1alpha1.CleanBackupPartial,
+		Status:  corev1.ConditionTrue,
+		Reason:  "CleanBackupPartiallyFailed",
+		Message: fmt.Sprintf("%d/%d targets failed (%s): %s", len(failed), len(results), strings.Join(failed, ", "), aggErr.Error()),
+	}, aggErr
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	errorutils "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+// cleanTimeoutAnnotation lets a Backup override how long its clean run is
+// allowed to take before its context is canceled, so a slow object store or
+// a huge volume-snapshot fan-out fails deterministically instead of running
+// until pod eviction. Borrowed from Velero's per-resource timeout
+// annotation.
+const cleanTimeoutAnnotation = "tidb.pingcap.com/clean-timeout"
+
+// cleanTimeoutFor returns the timeout to apply to a clean run: the
+// clean-timeout annotation on backup when present and valid, otherwise
+// bm.DefaultCleanTimeout. A zero result means no timeout should be applied.
+func (bm *Manager) cleanTimeoutFor(backup *v1alpha1.Backup) time.Duration {
+	if v, ok := backup.Annotations[cleanTimeoutAnnotation]; ok {
+		d, err := time.ParseDuration(v)
+		if err == nil {
+			return d
+		}
+		klog.Warningf("cluster %s backup %s has invalid %s annotation %q, falling back to default: %v", bm, backup.Name, cleanTimeoutAnnotation, v, err)
+	}
+	return bm.DefaultCleanTimeout
+}
+
+// cleanTimeoutError wraps a clean failure that happened because its own
+// per-backup deadline fired. cleanVictims tags errors this way because the
+// goroutine-local victimCtx that actually timed out isn't available to the
+// caller, which only sees the outer, un-timed batch ctx.
+type cleanTimeoutError struct {
+	err error
+}
+
+func (e *cleanTimeoutError) Error() string { return e.err.Error() }
+func (e *cleanTimeoutError) Unwrap() error { return e.err }
+
+// isCleanTimeout reports whether err, or any error wrapped inside an
+// errorutils.Aggregate, is a cleanTimeoutError.
+func isCleanTimeout(err error) bool {
+	if agg, ok := err.(errorutils.Aggregate); ok {
+		for _, e := range agg.Errors() {
+			if isCleanTimeout(e) {
+				return true
+			}
+		}
+		return false
+	}
+	var timeoutErr *cleanTimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// cleanFailureReason classifies a clean failure so operators can tell a
+// deterministic timeout apart from any other backend error. ctx.Err() covers
+// the single-backup path, where ctx itself carries the per-backup deadline;
+// isCleanTimeout covers the retention/batch path, where the deadline that
+// fired belongs to a per-victim context cleanVictims already discarded.
+func cleanFailureReason(ctx context.Context, err error) string {
+	if ctx.Err() == context.DeadlineExceeded || isCleanTimeout(err) {
+		return "CleanBackupTimeout"
+	}
+	return "CleanBackupDataFailed"
+}
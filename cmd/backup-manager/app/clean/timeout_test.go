@@ -0,0 +1,81 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clean
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCleanTimeoutForUsesAnnotationWhenValid(t *testing.T) {
+	bm := &Manager{Options: Options{DefaultCleanTimeout: time.Minute}}
+	backup := &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{cleanTimeoutAnnotation: "5m"},
+		},
+	}
+
+	if got := bm.cleanTimeoutFor(backup); got != 5*time.Minute {
+		t.Fatalf("got %v, want 5m", got)
+	}
+}
+
+func TestCleanTimeoutForFallsBackToDefaultWhenAnnotationMissing(t *testing.T) {
+	bm := &Manager{Options: Options{DefaultCleanTimeout: time.Minute}}
+	backup := &v1alpha1.Backup{}
+
+	if got := bm.cleanTimeoutFor(backup); got != time.Minute {
+		t.Fatalf("got %v, want 1m", got)
+	}
+}
+
+func TestCleanTimeoutForFallsBackToDefaultWhenAnnotationInvalid(t *testing.T) {
+	bm := &Manager{Options: Options{DefaultCleanTimeout: time.Minute}}
+	backup := &v1alpha1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{cleanTimeoutAnnotation: "not-a-duration"},
+		},
+	}
+
+	if got := bm.cleanTimeoutFor(backup); got != time.Minute {
+		t.Fatalf("got %v, want 1m fallback", got)
+	}
+}
+
+func TestCleanTimeoutForZeroMeansNoTimeout(t *testing.T) {
+	bm := &Manager{}
+	backup := &v1alpha1.Backup{}
+
+	if got := bm.cleanTimeoutFor(backup); got != 0 {
+		t.Fatalf("got %v, want 0", got)
+	}
+}
+
+func TestCleanFailureReasonDistinguishesTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	<-ctx.Done()
+
+	if got := cleanFailureReason(ctx, ctx.Err()); got != "CleanBackupTimeout" {
+		t.Fatalf("got %q, want CleanBackupTimeout", got)
+	}
+
+	if got := cleanFailureReason(context.Background(), context.Canceled); got != "CleanBackupDataFailed" {
+		t.Fatalf("got %q, want CleanBackupDataFailed", got)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupTarget is a separately reconciled storage destination (with its own
+// StorageProvider and credentials) that one or more Backups can write to
+// and be cleaned from, instead of embedding a StorageProvider directly.
+type BackupTarget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupTargetSpec   `json:"spec"`
+	Status BackupTargetStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupTargetList is a list of BackupTargets.
+type BackupTargetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []BackupTarget `json:"items"`
+}
+
+// BackupTargetSpec is the specification of a BackupTarget.
+type BackupTargetSpec struct {
+	// StorageProvider is where backups referencing this target write and
+	// delete their data.
+	StorageProvider `json:",inline"`
+}
+
+// BackupTargetStatus is the most recently observed status of a BackupTarget.
+type BackupTargetStatus struct {
+	// Phase is a terse, human-readable summary of the target's reconciled state.
+	Phase string `json:"phase,omitempty"`
+}
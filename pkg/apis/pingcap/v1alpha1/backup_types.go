@@ -0,0 +1,161 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// This file declares only the subset of the Backup CRD's API that
+// cmd/backup-manager/app/clean depends on (plus the fields/types this
+// series adds to it). The full field set (S3/GCS/Azure/local storage
+// providers, PD/TLS/BR tuning knobs, etc.) lives in the complete upstream
+// types.go and is intentionally not reproduced here.
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Backup is a backup of a TiDB cluster.
+type Backup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BackupSpec   `json:"spec"`
+	Status BackupStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackupList is a list of Backups.
+type BackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Backup `json:"items"`
+}
+
+// BackupMode represents how a Backup's data was (or will be) taken.
+type BackupMode string
+
+const (
+	// BackupModeSnapshot is a single logical snapshot of the cluster, taken via BR.
+	BackupModeSnapshot BackupMode = "snapshot"
+	// BackupModeVolumeSnapshot backs up a cluster via cloud-provider volume snapshots.
+	BackupModeVolumeSnapshot BackupMode = "volume-snapshot"
+)
+
+// BRConfig is the BR-specific configuration of a Backup.
+type BRConfig struct {
+	// ClusterNamespace is the namespace of the TiDB cluster being backed up.
+	ClusterNamespace string `json:"clusterNamespace,omitempty"`
+	// Cluster is the name of the TiDB cluster being backed up.
+	Cluster string `json:"cluster"`
+}
+
+// StorageProvider is the storage a Backup's data is written to (S3, GCS,
+// Azure, local, ...). The concrete provider union is defined in the full
+// upstream types.go; it is opaque here and only threaded through to
+// util.GetOptions / util.CalcVolSnapBackupSize.
+type StorageProvider struct {
+}
+
+// RetentionPolicy declares how many backups a clean run driven off policy
+// (rather than a single named Backup) should keep. Exactly one of Count,
+// MaxAge or GFS is expected to be set; applyRetention in
+// cmd/backup-manager/app/clean checks GFS first, then MaxAge, then Count,
+// and applies only the first one set.
+type RetentionPolicy struct {
+	// Count keeps the last N backups of each mode and deletes the rest.
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+	// MaxAge keeps backups started within this long of now.
+	// +optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+	// GFS keeps a grandfather-father-son daily/weekly/monthly matrix.
+	// +optional
+	GFS *GFSRetention `json:"gfs,omitempty"`
+}
+
+// GFSRetention configures a grandfather-father-son retention matrix: the
+// newest backup in each day/week/month bucket is kept, up to the given
+// number of buckets per granularity. Zero disables that granularity.
+type GFSRetention struct {
+	Daily   int32 `json:"daily,omitempty"`
+	Weekly  int32 `json:"weekly,omitempty"`
+	Monthly int32 `json:"monthly,omitempty"`
+}
+
+// BackupSpec is the specification of a Backup.
+type BackupSpec struct {
+	// Mode is how this backup was (or will be) taken.
+	Mode BackupMode `json:"mode,omitempty"`
+	// BR holds the BR-specific configuration when this backup was taken via BR.
+	// +optional
+	BR *BRConfig `json:"br,omitempty"`
+	// StorageProvider is where this backup's data is written, used when the
+	// Backup doesn't reference one or more BackupTargets by name.
+	StorageProvider `json:",inline"`
+	// BackupTargetNames resolves this backup's data to one or more
+	// separately-reconciled BackupTarget CRs instead of the embedded
+	// StorageProvider above, enabling cross-region mirror writes/deletes.
+	// +optional
+	BackupTargetNames []string `json:"backupTargetNames,omitempty"`
+	// RetentionPolicy, when set, lets ProcessCleanBackup act on every
+	// sibling Backup in the namespace selected by policy instead of only
+	// this one. Only a policy set on the Backup itself is read.
+	// +optional
+	RetentionPolicy *RetentionPolicy `json:"retentionPolicy,omitempty"`
+}
+
+// BackupStatus is the most recently observed status of a Backup.
+type BackupStatus struct {
+	// BackupPath is the full path this backup's data was written to.
+	BackupPath string `json:"backupPath,omitempty"`
+	// TimeStarted is when this backup run started.
+	TimeStarted metav1.Time `json:"timeStarted,omitempty"`
+	// CommitTs is the TiKV commit timestamp this backup is consistent as of.
+	CommitTs string `json:"commitTs,omitempty"`
+	// BackupSize is the size, in bytes, of the data this backup wrote.
+	BackupSize int64 `json:"backupSize,omitempty"`
+	// Conditions is the latest available observation of this backup's state.
+	Conditions []BackupCondition `json:"conditions,omitempty"`
+}
+
+// BackupConditionType represents the type of the current condition of a Backup.
+type BackupConditionType string
+
+const (
+	// BackupComplete means the backup has finished taking data.
+	BackupComplete BackupConditionType = "Complete"
+	// BackupFailed means the backup (or its cleanup) has failed.
+	BackupFailed BackupConditionType = "Failed"
+	// BackupClean means the backup's remote data has been cleaned up.
+	BackupClean BackupConditionType = "Clean"
+	// CleanBackupPartial means a multi-target backup had some but not all
+	// of its targets successfully cleaned.
+	CleanBackupPartial BackupConditionType = "CleanPartial"
+	// BackupCleanPlanned means a dry-run clean produced a plan of what it
+	// would remove without deleting anything.
+	BackupCleanPlanned BackupConditionType = "CleanPlanned"
+)
+
+// BackupCondition describes the observed state of a Backup at a point in time.
+type BackupCondition struct {
+	Type               BackupConditionType    `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+}
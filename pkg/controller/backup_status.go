@@ -0,0 +1,33 @@
+// Copyright 2019 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"github.com/pingcap/tidb-operator/pkg/apis/pingcap/v1alpha1"
+)
+
+// BackupUpdateStatus holds the Status fields a Backup update may touch
+// besides its conditions. Pointer fields are only applied when non-nil, so
+// a caller can update a subset of fields without clobbering the rest.
+type BackupUpdateStatus struct {
+	BackupSize         *int64
+	BackupSizeReadable *string
+}
+
+// BackupConditionUpdaterInterface updates a Backup's status, merging the
+// given condition into Status.Conditions and applying any fields set on
+// newStatus, then persisting the result.
+type BackupConditionUpdaterInterface interface {
+	Update(backup *v1alpha1.Backup, condition *v1alpha1.BackupCondition, newStatus *BackupUpdateStatus) error
+}